@@ -0,0 +1,56 @@
+package log
+
+import "context"
+
+type loggerKey struct{}
+
+// Logger is a sub-logger carrying persistent fields attached via With, for
+// use alongside the package-level API (Infoc, Warnc, ...). The zero
+// Logger is valid and behaves exactly like the package-level functions.
+type Logger struct {
+	fields []D
+	groups []string
+}
+
+// NewContext returns a copy of ctx carrying l, retrievable with
+// FromContext.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the Logger stored in ctx by NewContext, or the zero
+// Logger if ctx carries none.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return l
+	}
+	return &Logger{}
+}
+
+// With returns a copy of l whose fields are prepended to the fields of
+// every Infoc/Warnc/Errorc, Infov/Warnv/Errorv and Infow/Warnw/Errorw call
+// whose ctx carries it (see NewContext). Logger has no logging methods of
+// its own, and Info/Warn/Error do not consult a Logger. Field storage is
+// copy-on-write, so With is cheap and l remains safe for concurrent use.
+func (l *Logger) With(fields ...D) *Logger {
+	next := &Logger{groups: l.groups}
+	next.fields = make([]D, 0, len(l.fields)+len(fields))
+	next.fields = append(next.fields, l.fields...)
+	next.fields = append(next.fields, fields...)
+	return next
+}
+
+// WithGroup returns a copy of l that nests all of its fields under name:
+// those attached by With calls made before WithGroup as well as those
+// attached or logged after it.
+func (l *Logger) WithGroup(name string) *Logger {
+	next := &Logger{fields: l.fields}
+	next.groups = make([]string, 0, len(l.groups)+1)
+	next.groups = append(next.groups, l.groups...)
+	next.groups = append(next.groups, name)
+	return next
+}
+
+func (l *Logger) emit(ctx context.Context, level Level, fields ...D) {
+	emit(ctx, level, nestFields(l.groups, append(append([]D{}, l.fields...), fields...))...)
+}
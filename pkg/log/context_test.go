@@ -0,0 +1,102 @@
+package log
+
+import (
+	"context"
+	"testing"
+)
+
+type recordingHandler struct {
+	base
+	records []Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, Level) bool { return true }
+func (h *recordingHandler) Handle(_ context.Context, r Record) error {
+	r.Fields = h.fields(r.Fields)
+	h.records = append(h.records, r)
+	return nil
+}
+func (h *recordingHandler) WithAttrs(attrs []D) Handler {
+	next := &recordingHandler{base: h.base.withAttrs(attrs)}
+	return next
+}
+func (h *recordingHandler) WithGroup(name string) Handler {
+	next := &recordingHandler{base: h.base.withGroup(name)}
+	return next
+}
+func (h *recordingHandler) SetFormat(string) {}
+func (h *recordingHandler) Close() error     { return nil }
+
+func TestLoggerWithIsCopyOnWrite(t *testing.T) {
+	rec := &recordingHandler{}
+	old := h
+	h = rec
+	defer func() { h = old }()
+
+	base := FromContext(context.Background())
+	withUser := base.With(KV("user", "alice"))
+	withBoth := withUser.With(KV("req", "1"))
+
+	withUser.emit(context.Background(), _infoLevel, KV("msg", "one"))
+	withBoth.emit(context.Background(), _infoLevel, KV("msg", "two"))
+
+	if len(rec.records) != 2 {
+		t.Fatalf("got %d records, want 2", len(rec.records))
+	}
+	if n := len(rec.records[0].Fields); n != 2 {
+		t.Errorf("withUser record has %d fields, want 2 (user, msg)", n)
+	}
+	if n := len(rec.records[1].Fields); n != 3 {
+		t.Errorf("withBoth record has %d fields, want 3 (user, req, msg)", n)
+	}
+}
+
+func TestInfowUsesContextLogger(t *testing.T) {
+	rec := &recordingHandler{}
+	old := h
+	h = rec
+	defer func() { h = old }()
+
+	l := FromContext(context.Background()).WithGroup("grp").With(KV("k", "v"))
+	ctx := NewContext(context.Background(), l)
+	Infow(ctx, "msg", "hello")
+
+	if len(rec.records) != 1 {
+		t.Fatalf("got %d records, want 1", len(rec.records))
+	}
+	fields := rec.records[0].Fields
+	if len(fields) != 1 || fields[0].Key != "grp" {
+		t.Fatalf("fields = %+v, want a single \"grp\" field nesting k and msg", fields)
+	}
+	nested, ok := fields[0].Value.(map[string]interface{})
+	if !ok {
+		t.Fatalf("fields[0].Value = %T, want a nested map", fields[0].Value)
+	}
+	if nested["k"] != "v" {
+		t.Errorf("nested[\"k\"] = %v, want the context Logger's With(KV(\"k\",\"v\"))", nested["k"])
+	}
+	if nested["msg"] != "hello" {
+		t.Errorf("nested[\"msg\"] = %v, want \"hello\" from the Infow call", nested["msg"])
+	}
+}
+
+func TestLoggerWithGroupNests(t *testing.T) {
+	rec := &recordingHandler{}
+	old := h
+	h = rec
+	defer func() { h = old }()
+
+	l := FromContext(context.Background()).WithGroup("grp").With(KV("k", "v"))
+	l.emit(context.Background(), _infoLevel)
+
+	if len(rec.records) != 1 || len(rec.records[0].Fields) != 1 {
+		t.Fatalf("unexpected records: %+v", rec.records)
+	}
+	nested, ok := rec.records[0].Fields[0].Value.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected nested map, got %T", rec.records[0].Fields[0].Value)
+	}
+	if nested["k"] != "v" {
+		t.Errorf("nested[\"k\"] = %v, want \"v\"", nested["k"])
+	}
+}
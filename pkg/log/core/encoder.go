@@ -0,0 +1,60 @@
+// Package core defines the structured-field encoding primitives shared by
+// pkg/log's handlers. ObjectEncoder mirrors the zapcore.ObjectEncoder
+// contract so field types (see log.D) can be added without the handler
+// needing to know about every concrete Go type.
+package core
+
+import "time"
+
+// ObjectEncoder adds a single key/value field to a handler's in-flight
+// record. Each Add* method is responsible for converting value into
+// whatever on-wire representation the implementing encoder uses.
+type ObjectEncoder interface {
+	AddBool(key string, value bool)
+	AddComplex128(key string, value complex128)
+	AddComplex64(key string, value complex64)
+	AddFloat64(key string, value float64)
+	AddFloat32(key string, value float32)
+	AddInt(key string, value int)
+	AddInt64(key string, value int64)
+	AddInt32(key string, value int32)
+	AddInt16(key string, value int16)
+	AddInt8(key string, value int8)
+	AddString(key string, value string)
+	AddUint(key string, value uint)
+	AddUint64(key string, value uint64)
+	AddUint32(key string, value uint32)
+	AddUint16(key string, value uint16)
+	AddUint8(key string, value uint8)
+	AddByteString(key string, value []byte)
+	AddUintptr(key string, value uintptr)
+	AddTime(key string, value time.Time)
+	AddDuration(key string, value time.Duration)
+	// AddReflected falls back to reflection (via encoding/json) for any
+	// value that doesn't have a dedicated Add* method.
+	AddReflected(key string, value interface{}) error
+}
+
+// reserveTimeAndLevel renames any field whose key is "time" or "level" -
+// the keys EncodeJSON/EncodeLogfmt reserve for the record's own timestamp
+// and severity - so a caller-supplied field with one of those names can't
+// silently clobber (JSON) or duplicate (logfmt) the synthetic one.
+func reserveTimeAndLevel(fields []D) []D {
+	var out []D
+	for i, d := range fields {
+		if d.Key == "time" || d.Key == "level" {
+			if out == nil {
+				out = append([]D(nil), fields[:i]...)
+			}
+			out = append(out, KV("field_"+d.Key, d.Value))
+			continue
+		}
+		if out != nil {
+			out = append(out, d)
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jsonEncoder accumulates fields into a map and lets encoding/json do the
+// actual escaping; maps marshal their nested values recursively, so a
+// WithGroup'd field (stored as a single D whose Value is a
+// map[string]interface{}) comes out correctly nested with no extra work.
+type jsonEncoder struct {
+	fields map[string]interface{}
+}
+
+func newJSONEncoder() *jsonEncoder {
+	return &jsonEncoder{fields: make(map[string]interface{})}
+}
+
+func (e *jsonEncoder) AddBool(k string, v bool)             { e.fields[k] = v }
+func (e *jsonEncoder) AddComplex128(k string, v complex128) { e.fields[k] = v }
+func (e *jsonEncoder) AddComplex64(k string, v complex64)   { e.fields[k] = v }
+func (e *jsonEncoder) AddFloat64(k string, v float64)       { e.fields[k] = v }
+func (e *jsonEncoder) AddFloat32(k string, v float32)       { e.fields[k] = v }
+func (e *jsonEncoder) AddInt(k string, v int)               { e.fields[k] = v }
+func (e *jsonEncoder) AddInt64(k string, v int64)           { e.fields[k] = v }
+func (e *jsonEncoder) AddInt32(k string, v int32)           { e.fields[k] = v }
+func (e *jsonEncoder) AddInt16(k string, v int16)           { e.fields[k] = v }
+func (e *jsonEncoder) AddInt8(k string, v int8)             { e.fields[k] = v }
+func (e *jsonEncoder) AddString(k string, v string)         { e.fields[k] = v }
+func (e *jsonEncoder) AddUint(k string, v uint)             { e.fields[k] = v }
+func (e *jsonEncoder) AddUint64(k string, v uint64)         { e.fields[k] = v }
+func (e *jsonEncoder) AddUint32(k string, v uint32)         { e.fields[k] = v }
+func (e *jsonEncoder) AddUint16(k string, v uint16)         { e.fields[k] = v }
+func (e *jsonEncoder) AddUint8(k string, v uint8)           { e.fields[k] = v }
+func (e *jsonEncoder) AddByteString(k string, v []byte)     { e.fields[k] = string(v) }
+func (e *jsonEncoder) AddUintptr(k string, v uintptr)       { e.fields[k] = v }
+func (e *jsonEncoder) AddTime(k string, v time.Time)        { e.fields[k] = v.Format(time.RFC3339Nano) }
+func (e *jsonEncoder) AddDuration(k string, v time.Duration) {
+	e.fields[k] = v.String()
+}
+func (e *jsonEncoder) AddReflected(k string, v interface{}) error {
+	e.fields[k] = v
+	return nil
+}
+
+// EncodeJSON renders level/t/fields as a single JSON object, with "time"
+// in RFC3339Nano and "level" as the level's name. "time"/"level" are
+// reserved for those synthetic fields; a caller field using either name is
+// renamed (see reserveTimeAndLevel) rather than silently overwriting them.
+func EncodeJSON(t time.Time, level Level, fields []D) ([]byte, error) {
+	e := newJSONEncoder()
+	e.fields["time"] = t.Format(time.RFC3339Nano)
+	e.fields["level"] = level.String()
+	for _, d := range reserveTimeAndLevel(fields) {
+		d.AddTo(e)
+	}
+	return json.Marshal(e.fields)
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEncodeJSON(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	b, err := EncodeJSON(ts, LevelInfo, []D{
+		KV("msg", `say "hi"` + "\nnewline"),
+		KV("count", 3),
+	})
+	if err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("output isn't valid JSON: %v\n%s", err, b)
+	}
+	if got["time"] != ts.Format(time.RFC3339Nano) {
+		t.Errorf("time = %v, want %v", got["time"], ts.Format(time.RFC3339Nano))
+	}
+	if got["level"] != LevelInfo.String() {
+		t.Errorf("level = %v, want %v", got["level"], LevelInfo.String())
+	}
+	if got["msg"] != `say "hi"`+"\nnewline" {
+		t.Errorf("msg = %q, want the quote/newline preserved", got["msg"])
+	}
+	if got["count"] != float64(3) {
+		t.Errorf("count = %v, want 3", got["count"])
+	}
+}
+
+func TestEncodeJSONNestsWithGroup(t *testing.T) {
+	nested := map[string]interface{}{"k": "v"}
+	b, err := EncodeJSON(time.Now(), LevelInfo, []D{KV("grp", nested)})
+	if err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("output isn't valid JSON: %v\n%s", err, b)
+	}
+	grp, ok := got["grp"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("grp = %T, want nested object", got["grp"])
+	}
+	if grp["k"] != "v" {
+		t.Errorf("grp[\"k\"] = %v, want \"v\"", grp["k"])
+	}
+}
+
+func TestEncodeJSONReservesTimeAndLevel(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	b, err := EncodeJSON(ts, LevelInfo, []D{KV("time", "nope"), KV("level", "nope")})
+	if err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("output isn't valid JSON: %v\n%s", err, b)
+	}
+	if got["time"] != ts.Format(time.RFC3339Nano) {
+		t.Errorf("time = %v, want the record's real timestamp, not clobbered by the caller field", got["time"])
+	}
+	if got["level"] != LevelInfo.String() {
+		t.Errorf("level = %v, want the record's real level, not clobbered by the caller field", got["level"])
+	}
+	if got["field_time"] != "nope" || got["field_level"] != "nope" {
+		t.Errorf("got = %+v, want the caller's colliding fields renamed to field_time/field_level", got)
+	}
+}
@@ -0,0 +1,25 @@
+package core
+
+// Level identifies the severity of a log Record, ordered from least to most
+// severe.
+type Level int8
+
+const (
+	LevelInfo Level = iota
+	LevelWarn
+	LevelError
+)
+
+// String implements fmt.Stringer.
+func (l Level) String() string {
+	switch l {
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logfmtEncoder writes "key=value key2=value2 ..." pairs, quoting any
+// value that contains a space, "=", '"' or a non-printable character (most
+// importantly newlines, which would otherwise split one record across
+// multiple physical lines).
+type logfmtEncoder struct {
+	buf   bytes.Buffer
+	wrote bool
+}
+
+func newLogfmtEncoder() *logfmtEncoder {
+	return &logfmtEncoder{}
+}
+
+func (e *logfmtEncoder) writeKV(key, value string) {
+	if e.wrote {
+		e.buf.WriteByte(' ')
+	}
+	e.wrote = true
+	e.buf.WriteString(key)
+	e.buf.WriteByte('=')
+	if needsQuote(value) {
+		e.buf.WriteString(strconv.Quote(value))
+	} else {
+		e.buf.WriteString(value)
+	}
+}
+
+// needsQuote reports whether value must be quoted to keep a logfmt line to
+// a single physical line and its key=value pairs unambiguous.
+func needsQuote(value string) bool {
+	if strings.ContainsAny(value, " =\"") {
+		return true
+	}
+	for _, r := range value {
+		if !strconv.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *logfmtEncoder) AddBool(k string, v bool) { e.writeKV(k, strconv.FormatBool(v)) }
+func (e *logfmtEncoder) AddComplex128(k string, v complex128) {
+	e.writeKV(k, strconv.FormatComplex(v, 'g', -1, 128))
+}
+func (e *logfmtEncoder) AddComplex64(k string, v complex64) {
+	e.writeKV(k, strconv.FormatComplex(complex128(v), 'g', -1, 64))
+}
+func (e *logfmtEncoder) AddFloat64(k string, v float64) {
+	e.writeKV(k, strconv.FormatFloat(v, 'g', -1, 64))
+}
+func (e *logfmtEncoder) AddFloat32(k string, v float32) {
+	e.writeKV(k, strconv.FormatFloat(float64(v), 'g', -1, 32))
+}
+func (e *logfmtEncoder) AddInt(k string, v int)       { e.writeKV(k, strconv.Itoa(v)) }
+func (e *logfmtEncoder) AddInt64(k string, v int64)   { e.writeKV(k, strconv.FormatInt(v, 10)) }
+func (e *logfmtEncoder) AddInt32(k string, v int32)   { e.writeKV(k, strconv.FormatInt(int64(v), 10)) }
+func (e *logfmtEncoder) AddInt16(k string, v int16)   { e.writeKV(k, strconv.FormatInt(int64(v), 10)) }
+func (e *logfmtEncoder) AddInt8(k string, v int8)     { e.writeKV(k, strconv.FormatInt(int64(v), 10)) }
+func (e *logfmtEncoder) AddString(k string, v string) { e.writeKV(k, v) }
+func (e *logfmtEncoder) AddUint(k string, v uint)     { e.writeKV(k, strconv.FormatUint(uint64(v), 10)) }
+func (e *logfmtEncoder) AddUint64(k string, v uint64) { e.writeKV(k, strconv.FormatUint(v, 10)) }
+func (e *logfmtEncoder) AddUint32(k string, v uint32) {
+	e.writeKV(k, strconv.FormatUint(uint64(v), 10))
+}
+func (e *logfmtEncoder) AddUint16(k string, v uint16) {
+	e.writeKV(k, strconv.FormatUint(uint64(v), 10))
+}
+func (e *logfmtEncoder) AddUint8(k string, v uint8) {
+	e.writeKV(k, strconv.FormatUint(uint64(v), 10))
+}
+func (e *logfmtEncoder) AddByteString(k string, v []byte) { e.writeKV(k, string(v)) }
+func (e *logfmtEncoder) AddUintptr(k string, v uintptr) {
+	e.writeKV(k, strconv.FormatUint(uint64(v), 16))
+}
+func (e *logfmtEncoder) AddTime(k string, v time.Time) { e.writeKV(k, v.Format(time.RFC3339Nano)) }
+func (e *logfmtEncoder) AddDuration(k string, v time.Duration) {
+	e.writeKV(k, v.String())
+}
+
+// AddReflected falls back to JSON for anything without a dedicated Add*
+// method (including the map[string]interface{} WithGroup produces).
+// logfmt itself has no native nesting, so a grouped field comes out as a
+// single quoted JSON blob, e.g. grp="{\"k\":\"v\"}".
+func (e *logfmtEncoder) AddReflected(k string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.writeKV(k, string(b))
+	return nil
+}
+
+// EncodeLogfmt renders level/t/fields as a single logfmt line. "time"/
+// "level" are reserved for those synthetic fields; a caller field using
+// either name is renamed (see reserveTimeAndLevel) rather than emitting a
+// second, ambiguous key=value pair under the same name.
+func EncodeLogfmt(t time.Time, level Level, fields []D) ([]byte, error) {
+	e := newLogfmtEncoder()
+	e.writeKV("time", t.Format(time.RFC3339Nano))
+	e.writeKV("level", level.String())
+	for _, d := range reserveTimeAndLevel(fields) {
+		d.AddTo(e)
+	}
+	return e.buf.Bytes(), nil
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeLogfmtQuotesSpecialValues(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	b, err := EncodeLogfmt(ts, LevelInfo, []D{
+		KV("msg", "hello world"),
+		KV("expr", "a=b"),
+		KV("plain", "nospaces"),
+	})
+	if err != nil {
+		t.Fatalf("EncodeLogfmt: %v", err)
+	}
+	got := string(b)
+
+	if !strings.Contains(got, `msg="hello world"`) {
+		t.Errorf("output %q missing quoted msg", got)
+	}
+	if !strings.Contains(got, `expr="a=b"`) {
+		t.Errorf("output %q missing quoted expr", got)
+	}
+	if !strings.Contains(got, "plain=nospaces") {
+		t.Errorf("output %q should leave plain unquoted", got)
+	}
+}
+
+func TestEncodeLogfmtQuotesControlCharacters(t *testing.T) {
+	b, err := EncodeLogfmt(time.Now(), LevelInfo, []D{KV("msg", "line1\nline2")})
+	if err != nil {
+		t.Fatalf("EncodeLogfmt: %v", err)
+	}
+	got := string(b)
+	if strings.Contains(got, "\n") {
+		t.Fatalf("output %q contains a literal newline, want it quoted onto one line", got)
+	}
+	if !strings.Contains(got, `msg="line1\nline2"`) {
+		t.Errorf("output %q missing quoted msg", got)
+	}
+}
+
+func TestEncodeLogfmtReservesTimeAndLevel(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	b, err := EncodeLogfmt(ts, LevelInfo, []D{KV("time", "nope"), KV("level", "nope")})
+	if err != nil {
+		t.Fatalf("EncodeLogfmt: %v", err)
+	}
+	got := string(b)
+	if !strings.Contains(got, "time="+ts.Format(time.RFC3339Nano)) {
+		t.Errorf("output %q, want the record's real timestamp, not clobbered by the caller field", got)
+	}
+	if !strings.Contains(got, "level="+LevelInfo.String()) {
+		t.Errorf("output %q, want the record's real level, not clobbered by the caller field", got)
+	}
+	if !strings.Contains(got, "field_time=nope") || !strings.Contains(got, "field_level=nope") {
+		t.Errorf("output %q, want the caller's colliding fields renamed to field_time/field_level", got)
+	}
+}
+
+func TestEncodeLogfmtNestsWithGroupAsJSON(t *testing.T) {
+	nested := map[string]interface{}{"k": "v"}
+	b, err := EncodeLogfmt(time.Now(), LevelInfo, []D{KV("grp", nested)})
+	if err != nil {
+		t.Fatalf("EncodeLogfmt: %v", err)
+	}
+	got := string(b)
+	if !strings.Contains(got, `grp="{\"k\":\"v\"}"`) {
+		t.Errorf("output %q, want a quoted JSON blob for grp", got)
+	}
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	xtime "github.com/bilibili/Kratos/pkg/time"
+)
+
+// D represents a single entry of structured logging data.
+// type D map[string]interface{}
+type D struct {
+	Key   string
+	Value interface{}
+}
+
+// KV returns a D for logging field key/value.
+func KV(key string, value interface{}) D {
+	return D{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// AddTo exports a field through the ObjectEncoder interface. It's primarily
+// useful to library authors, and shouldn't be necessary in most applications.
+func (d D) AddTo(enc ObjectEncoder) {
+	var err error
+	switch val := d.Value.(type) {
+	case bool:
+		enc.AddBool(d.Key, val)
+	case complex128:
+		enc.AddComplex128(d.Key, val)
+	case complex64:
+		enc.AddComplex64(d.Key, val)
+	case float64:
+		enc.AddFloat64(d.Key, val)
+	case float32:
+		enc.AddFloat32(d.Key, val)
+	case int:
+		enc.AddInt(d.Key, val)
+	case int64:
+		enc.AddInt64(d.Key, val)
+	case int32:
+		enc.AddInt32(d.Key, val)
+	case int16:
+		enc.AddInt16(d.Key, val)
+	case int8:
+		enc.AddInt8(d.Key, val)
+	case string:
+		enc.AddString(d.Key, val)
+	case uint:
+		enc.AddUint(d.Key, val)
+	case uint64:
+		enc.AddUint64(d.Key, val)
+	case uint32:
+		enc.AddUint32(d.Key, val)
+	case uint16:
+		enc.AddUint16(d.Key, val)
+	case uint8:
+		enc.AddUint8(d.Key, val)
+	case []byte:
+		enc.AddByteString(d.Key, val)
+	case uintptr:
+		enc.AddUintptr(d.Key, val)
+	case time.Time:
+		enc.AddTime(d.Key, val)
+	case xtime.Time:
+		enc.AddTime(d.Key, val.Time())
+	case time.Duration:
+		enc.AddDuration(d.Key, val)
+	case xtime.Duration:
+		enc.AddDuration(d.Key, time.Duration(val))
+	case error:
+		enc.AddString(d.Key, val.Error())
+	case fmt.Stringer:
+		enc.AddString(d.Key, val.String())
+	default:
+		err = enc.AddReflected(d.Key, val)
+	}
+
+	if err != nil {
+		enc.AddString(fmt.Sprintf("%sError", d.Key), err.Error())
+	}
+}
+
+// Record is a single structured log entry passed to a Handler.
+type Record struct {
+	Level  Level
+	Fields []D
+}
+
+// Handler is the sink-facing logging interface, modeled on log/slog's
+// Handler contract so third-party sinks (Kafka, Loki, syslog, OTLP, ...)
+// can be plugged in without forking pkg/log. It lives in pkg/log/core
+// (rather than pkg/log itself) so subpackages like pkg/log/filter can
+// depend on it without importing pkg/log back.
+type Handler interface {
+	// Enabled reports whether the handler processes records at the given
+	// level. Callers should check Enabled before doing expensive work to
+	// build a Record.
+	Enabled(ctx context.Context, level Level) bool
+	// Handle processes the Record. Handle is only called when Enabled
+	// returns true for the record's level.
+	Handle(ctx context.Context, r Record) error
+	// WithAttrs returns a new Handler whose Record.Fields always include
+	// attrs ahead of any fields passed to Handle.
+	WithAttrs(attrs []D) Handler
+	// WithGroup returns a new Handler that nests all fields added by
+	// subsequent Handle/WithAttrs calls under name. The handlers in pkg/log
+	// (stdout, file, otlp) also put attrs from earlier WithAttrs calls
+	// under name.
+	WithGroup(name string) Handler
+
+	// SetFormat configures the printf-style layout used by handlers that
+	// render to a line-oriented sink (stdout, file). Handlers that don't
+	// support it may treat this as a no-op.
+	SetFormat(format string)
+	// Close releases resources held by the handler (open files, network
+	// connections, background goroutines, ...).
+	Close() error
+}
@@ -0,0 +1,385 @@
+package log
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+func init() {
+	RegisterHandler("file", func(conf HandlerConfig) (Handler, error) {
+		fc, ok := conf.Raw.(FileConfig)
+		if !ok {
+			return nil, fmt.Errorf("log: file handler requires a FileConfig, got %T", conf.Raw)
+		}
+		return NewFile(fc), nil
+	})
+}
+
+// AsyncDropPolicy controls what a file handler does when its ring buffer
+// is full and the writer goroutine can't keep up.
+type AsyncDropPolicy int8
+
+const (
+	// DropOldest discards the oldest buffered entry to make room for the
+	// new one.
+	DropOldest AsyncDropPolicy = iota
+	// DropNewest discards the entry that was about to be enqueued.
+	DropNewest
+	// Block makes Handle wait for room instead of dropping anything, until
+	// Close has been called; after that an entry that doesn't fit is
+	// dropped.
+	Block
+)
+
+// FileConfig configures the "file" handler when enabled through
+// Config.Handlers.
+type FileConfig struct {
+	Dir string
+	// BufferSize sizes the bufio.Writer in front of the log file, in bytes.
+	// Defaults to bufio's default size.
+	BufferSize int64
+	RotateSize int64
+	MaxLogFile int
+
+	// AsyncBufferEntries sizes the ring buffer Handle writes into; it's
+	// rounded up to the next power of two. Defaults to 1024.
+	AsyncBufferEntries int
+	// AsyncFlushInterval bounds how long a buffered entry can sit before
+	// the drain goroutine writes it out. Defaults to 200ms.
+	AsyncFlushInterval time.Duration
+	// AsyncDropPolicy says what to do when the ring buffer is full.
+	// Defaults to DropOldest.
+	AsyncDropPolicy AsyncDropPolicy
+	// CloseTimeout bounds how long Close() waits for the ring buffer to
+	// drain. Defaults to 5s.
+	CloseTimeout time.Duration
+}
+
+// FileStats reports a file handler's Stats().
+type FileStats struct {
+	// Dropped is the total number of records discarded because the ring
+	// buffer was full (see AsyncDropPolicy).
+	Dropped uint64
+	// Flushed is the total number of records written to disk.
+	Flushed uint64
+	// Buffered is the number of records currently sitting in the ring
+	// buffer, awaiting the drain goroutine.
+	Buffered int64
+}
+
+// fileWriter holds the mutable, mutex-guarded file/rotation state shared by
+// every handler derived from the same NewFile call (via WithAttrs/WithGroup)
+// and owned exclusively by the drain goroutine otherwise.
+type fileWriter struct {
+	mu sync.Mutex
+
+	dir        string
+	bufSize    int
+	rotateSize int64
+	maxLogFile int
+
+	f       *os.File
+	w       *bufio.Writer
+	written int64
+}
+
+func (s *fileWriter) open() error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	fd, err := os.OpenFile(filepath.Join(s.dir, "app.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := fd.Stat()
+	if err != nil {
+		fd.Close()
+		return err
+	}
+	s.f = fd
+	if s.bufSize > 0 {
+		s.w = bufio.NewWriterSize(fd, s.bufSize)
+	} else {
+		s.w = bufio.NewWriter(fd)
+	}
+	s.written = fi.Size()
+	return nil
+}
+
+func (s *fileWriter) rotate() error {
+	s.w.Flush()
+	s.f.Close()
+	for i := s.maxLogFile - 1; i >= 1; i-- {
+		old := filepath.Join(s.dir, fmt.Sprintf("app.log.%d", i))
+		renamed := filepath.Join(s.dir, fmt.Sprintf("app.log.%d", i+1))
+		os.Rename(old, renamed)
+	}
+	if s.maxLogFile > 0 {
+		os.Rename(filepath.Join(s.dir, "app.log"), filepath.Join(s.dir, "app.log.1"))
+	}
+	return s.open()
+}
+
+// writeBatch appends every line in lines to the file as a single write,
+// rotating first once the file has already grown past rotateSize. If the
+// file couldn't be opened by NewFile, the open is retried here and its
+// error returned.
+func (s *fileWriter) writeBatch(lines []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	if s.rotateSize > 0 && s.written > s.rotateSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	var batch []byte
+	for _, line := range lines {
+		batch = append(batch, line...)
+		batch = append(batch, '\n')
+	}
+	// s.w is empty here (every batch ends with Flush), so bufio hands a
+	// batch larger than its buffer to the file in one Write call.
+	n, err := s.w.Write(batch)
+	s.written += int64(n)
+	if err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+func (s *fileWriter) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	return s.f.Close()
+}
+
+// asyncState is the producer/consumer machinery shared by every handler
+// derived from the same NewFile call. Handle pushes onto a bounded
+// lock-free ring buffer and never blocks on disk I/O (unless
+// AsyncDropPolicy is Block); a single drain goroutine batches entries into
+// one write per wakeup and rotates the file as needed.
+type asyncState struct {
+	ring       *ringBuffer
+	dropPolicy AsyncDropPolicy
+	flushEvery time.Duration
+	closeAfter time.Duration
+	writer     *fileWriter
+	format     atomic.Value // string
+
+	dropped uint64
+	flushed uint64
+
+	wake    chan struct{}
+	done    chan struct{}
+	drained chan struct{}
+	sigCh   chan os.Signal
+}
+
+type fileRecord struct {
+	level  Level
+	fields []D
+}
+
+// file writes records to a rotating log file under dir, using the %T %L %M
+// printf-style template (see SetFormat).
+type file struct {
+	base
+	state *asyncState
+}
+
+// NewFile returns a Handler that writes to a rotating file under
+// conf.Dir. Writes are batched through an async ring buffer (see
+// FileConfig); producers never block on disk I/O unless
+// conf.AsyncDropPolicy is Block.
+func NewFile(conf FileConfig) Handler {
+	entries := conf.AsyncBufferEntries
+	if entries <= 0 {
+		entries = 1024
+	}
+	flushEvery := conf.AsyncFlushInterval
+	if flushEvery <= 0 {
+		flushEvery = 200 * time.Millisecond
+	}
+	closeAfter := conf.CloseTimeout
+	if closeAfter <= 0 {
+		closeAfter = 5 * time.Second
+	}
+
+	s := &asyncState{
+		ring:       newRingBuffer(entries),
+		dropPolicy: conf.AsyncDropPolicy,
+		flushEvery: flushEvery,
+		closeAfter: closeAfter,
+		writer: &fileWriter{
+			dir:        conf.Dir,
+			bufSize:    int(conf.BufferSize),
+			rotateSize: conf.RotateSize,
+			maxLogFile: conf.MaxLogFile,
+		},
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		drained: make(chan struct{}),
+		sigCh:   make(chan os.Signal, 1),
+	}
+	s.format.Store("%T %L %M")
+	if err := s.writer.open(); err != nil {
+		// NewFile has no error return, so report the failure through the
+		// package-level logger; writeBatch retries the open on the first
+		// write and surfaces its error again there.
+		Error("log: open file handler: %v", err)
+	}
+
+	signal.Notify(s.sigCh, syscall.SIGUSR1)
+	go s.drain()
+
+	return &file{state: s}
+}
+
+// drain is the single consumer goroutine: it batches whatever's in the
+// ring buffer into one write, then sleeps until woken by a producer, the
+// flush ticker, or a SIGUSR1 (glog's flushDaemon equivalent).
+func (s *asyncState) drain() {
+	defer close(s.drained)
+	ticker := time.NewTicker(s.flushEvery)
+	defer ticker.Stop()
+	for {
+		s.flush()
+		select {
+		case <-s.done:
+			s.flush()
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		case <-s.sigCh:
+		}
+	}
+}
+
+// flush drains every entry currently in the ring buffer into a single
+// batched write.
+func (s *asyncState) flush() {
+	var lines []string
+	for {
+		r, ok := s.ring.pop()
+		if !ok {
+			break
+		}
+		lines = append(lines, render(s.format.Load().(string), time.Now(), r.level, r.fields))
+	}
+	if len(lines) == 0 {
+		return
+	}
+	if err := s.writer.writeBatch(lines); err != nil {
+		fmt.Fprintf(os.Stderr, "log: file write: %v\n", err)
+		return
+	}
+	atomic.AddUint64(&s.flushed, uint64(len(lines)))
+}
+
+// push enqueues r, honoring AsyncDropPolicy when the ring buffer is full.
+func (s *asyncState) push(r fileRecord) {
+	if s.ring.push(r) {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+		return
+	}
+	switch s.dropPolicy {
+	case DropNewest:
+		atomic.AddUint64(&s.dropped, 1)
+	case Block:
+		for !s.ring.push(r) {
+			select {
+			case <-s.done:
+				// drain has taken its last flush and stopped popping the
+				// ring; nothing will ever free up space, so drop instead
+				// of spinning forever.
+				atomic.AddUint64(&s.dropped, 1)
+				return
+			default:
+				runtime.Gosched()
+			}
+		}
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	default: // DropOldest
+		if _, ok := s.ring.pop(); ok {
+			atomic.AddUint64(&s.dropped, 1)
+		}
+		if !s.ring.push(r) {
+			atomic.AddUint64(&s.dropped, 1)
+			return
+		}
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Stats reports the handler's dropped/flushed/buffered counters.
+func (f *file) Stats() FileStats {
+	return FileStats{
+		Dropped:  atomic.LoadUint64(&f.state.dropped),
+		Flushed:  atomic.LoadUint64(&f.state.flushed),
+		Buffered: f.state.ring.len(),
+	}
+}
+
+func (f *file) Enabled(context.Context, Level) bool { return true }
+
+func (f *file) Handle(ctx context.Context, r Record) error {
+	f.state.push(fileRecord{level: r.Level, fields: f.fields(r.Fields)})
+	return nil
+}
+
+func (f *file) WithAttrs(attrs []D) Handler {
+	next := *f
+	next.base = f.base.withAttrs(attrs)
+	return &next
+}
+
+func (f *file) WithGroup(name string) Handler {
+	next := *f
+	next.base = f.base.withGroup(name)
+	return &next
+}
+
+func (f *file) SetFormat(format string) {
+	if format != "" {
+		f.state.format.Store(format)
+	}
+}
+
+// Close stops the drain goroutine and flushes any remaining buffered
+// entries, waiting up to the handler's CloseTimeout.
+func (f *file) Close() error {
+	close(f.state.done)
+	select {
+	case <-f.state.drained:
+	case <-time.After(f.state.closeAfter):
+		return fmt.Errorf("log: file handler close timed out after %s with %d entries still buffered", f.state.closeAfter, f.state.ring.len())
+	}
+	signal.Stop(f.state.sigCh)
+	return f.state.writer.close()
+}
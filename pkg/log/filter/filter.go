@@ -0,0 +1,140 @@
+// Package filter implements a composable redaction/sampling pipeline that
+// wraps a log/core.Handler. It replaces pkg/log's old flat Config.Filter
+// field and its hardcoded field-name matching: handlers can now stack
+// multiple filters, e.g. redact PII globally on one sink while dropping
+// debug traffic on another.
+package filter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bilibili/Kratos/pkg/log/core"
+)
+
+const _redacted = "***"
+
+// FilterOption configures a filter handler built by NewFilter.
+type FilterOption func(*options)
+
+type options struct {
+	hasLevel bool
+	level    core.Level
+	keys     map[string]struct{}
+	values   []string
+	fn       func(ctx context.Context, level core.Level, fields ...core.D) bool
+}
+
+// FilterLevel drops records below level.
+func FilterLevel(level core.Level) FilterOption {
+	return func(o *options) {
+		o.hasLevel = true
+		o.level = level
+	}
+}
+
+// FilterKey redacts fields whose key matches one of keys, regardless of
+// how deeply they're nested inside a WithGroup'd D.
+func FilterKey(keys ...string) FilterOption {
+	return func(o *options) {
+		if o.keys == nil {
+			o.keys = make(map[string]struct{}, len(keys))
+		}
+		for _, k := range keys {
+			o.keys[k] = struct{}{}
+		}
+	}
+}
+
+// FilterValue redacts string fields whose value contains one of values.
+func FilterValue(values ...string) FilterOption {
+	return func(o *options) {
+		o.values = append(o.values, values...)
+	}
+}
+
+// FilterFunc drops a record entirely when fn returns true.
+func FilterFunc(fn func(ctx context.Context, level core.Level, fields ...core.D) bool) FilterOption {
+	return func(o *options) {
+		o.fn = fn
+	}
+}
+
+// handler wraps next, applying redaction/sampling to every Record before
+// delegating.
+type handler struct {
+	next core.Handler
+	opt  options
+}
+
+// NewFilter wraps next with a filter pipeline configured by opts.
+func NewFilter(next core.Handler, opts ...FilterOption) core.Handler {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &handler{next: next, opt: o}
+}
+
+func (h *handler) Enabled(ctx context.Context, level core.Level) bool {
+	if h.opt.hasLevel && level < h.opt.level {
+		return false
+	}
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r core.Record) error {
+	if h.opt.fn != nil && h.opt.fn(ctx, r.Level, r.Fields...) {
+		return nil
+	}
+	r.Fields = h.redact(r.Fields)
+	return h.next.Handle(ctx, r)
+}
+
+// redact replaces matched keys/values with "***" before the encoder ever
+// sees them. Fields nested under WithGroup are stored as a single D whose
+// Value is a map[string]interface{} (see (log.Handler).WithGroup); redact
+// recurses into those so matches are found at any nesting depth.
+func (h *handler) redact(fields []core.D) []core.D {
+	out := make([]core.D, len(fields))
+	for i, d := range fields {
+		out[i] = h.redactOne(d)
+	}
+	return out
+}
+
+func (h *handler) redactOne(d core.D) core.D {
+	if _, ok := h.opt.keys[d.Key]; ok {
+		d.Value = _redacted
+		return d
+	}
+	if nested, ok := d.Value.(map[string]interface{}); ok {
+		next := make(map[string]interface{}, len(nested))
+		for k, v := range nested {
+			next[k] = h.redactOne(core.KV(k, v)).Value
+		}
+		d.Value = next
+		return d
+	}
+	if s, ok := d.Value.(string); ok {
+		for _, v := range h.opt.values {
+			if strings.Contains(s, v) {
+				d.Value = _redacted
+				break
+			}
+		}
+	}
+	return d
+}
+
+func (h *handler) WithAttrs(attrs []core.D) core.Handler {
+	return &handler{next: h.next.WithAttrs(attrs), opt: h.opt}
+}
+
+func (h *handler) WithGroup(name string) core.Handler {
+	return &handler{next: h.next.WithGroup(name), opt: h.opt}
+}
+
+func (h *handler) SetFormat(format string) { h.next.SetFormat(format) }
+
+func (h *handler) Close() error { return h.next.Close() }
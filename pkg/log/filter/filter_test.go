@@ -0,0 +1,82 @@
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bilibili/Kratos/pkg/log/core"
+)
+
+// recorder is a minimal core.Handler that just remembers the last Record
+// it was given.
+type recorder struct {
+	last core.Record
+}
+
+func (r *recorder) Enabled(context.Context, core.Level) bool { return true }
+func (r *recorder) Handle(_ context.Context, rec core.Record) error {
+	r.last = rec
+	return nil
+}
+func (r *recorder) WithAttrs(attrs []core.D) core.Handler { return r }
+func (r *recorder) WithGroup(string) core.Handler         { return r }
+func (r *recorder) SetFormat(string)                      {}
+func (r *recorder) Close() error                          { return nil }
+
+func TestFilterKeyRedactsTopLevel(t *testing.T) {
+	rec := &recorder{}
+	h := NewFilter(rec, FilterKey("password"))
+	if err := h.Handle(context.Background(), core.Record{
+		Level:  core.LevelInfo,
+		Fields: []core.D{core.KV("password", "abc"), core.KV("user", "alice")},
+	}); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	got := fieldMap(rec.last.Fields)
+	if got["password"] != _redacted {
+		t.Errorf("password = %v, want %v", got["password"], _redacted)
+	}
+	if got["user"] != "alice" {
+		t.Errorf("user = %v, want unchanged", got["user"])
+	}
+}
+
+func TestFilterKeyRedactsNestedGroup(t *testing.T) {
+	rec := &recorder{}
+	h := NewFilter(rec, FilterKey("password"))
+	nested := map[string]interface{}{"password": "abc", "user": "alice"}
+	if err := h.Handle(context.Background(), core.Record{
+		Level:  core.LevelInfo,
+		Fields: []core.D{core.KV("grp", nested)},
+	}); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	got := rec.last.Fields[0].Value.(map[string]interface{})
+	if got["password"] != _redacted {
+		t.Errorf("password = %v, want %v", got["password"], _redacted)
+	}
+	if got["user"] != "alice" {
+		t.Errorf("user = %v, want unchanged", got["user"])
+	}
+}
+
+func TestFilterFuncDropsRecord(t *testing.T) {
+	rec := &recorder{}
+	h := NewFilter(rec, FilterFunc(func(ctx context.Context, level core.Level, fields ...core.D) bool {
+		return level == core.LevelInfo
+	}))
+	if err := h.Handle(context.Background(), core.Record{Level: core.LevelInfo}); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if rec.last.Fields != nil {
+		t.Errorf("expected dropped record to leave recorder untouched, got %+v", rec.last)
+	}
+}
+
+func fieldMap(fields []core.D) map[string]interface{} {
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	return m
+}
@@ -0,0 +1,72 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bilibili/Kratos/pkg/log/core"
+)
+
+// render expands a SetFormat template against a single record, producing
+// the line written by a line-oriented handler such as stdout or file.
+// format may also name a structured encoder ("json", "logfmt") instead of
+// a %-template; see the doc comment on SetFormat.
+func render(format string, t time.Time, level Level, fields []D) string {
+	switch format {
+	case "json":
+		b, err := core.EncodeJSON(t, level, fields)
+		if err != nil {
+			return fmt.Sprintf("log: json encode error: %v", err)
+		}
+		return string(b)
+	case "logfmt":
+		b, err := core.EncodeLogfmt(t, level, fields)
+		if err != nil {
+			return fmt.Sprintf("log: logfmt encode error: %v", err)
+		}
+		return string(b)
+	}
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || i == len(format)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch format[i] {
+		case 'T':
+			b.WriteString(t.Format("15:04:05.999"))
+		case 't':
+			b.WriteString(t.Format("15:04:05"))
+		case 'D':
+			b.WriteString(t.Format("2006/01/02"))
+		case 'd':
+			b.WriteString(t.Format("01/02"))
+		case 'L':
+			b.WriteString(level.String())
+		case 'M':
+			renderFields(&b, fields)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(format[i])
+		}
+	}
+	return b.String()
+}
+
+// renderFields writes fields as "key=value" pairs separated by spaces. The
+// conventional _log message field, if present, is rendered bare.
+func renderFields(b *strings.Builder, fields []D) {
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if f.Key == _log {
+			fmt.Fprintf(b, "%v", f.Value)
+			continue
+		}
+		fmt.Fprintf(b, "%s=%v", f.Key, f.Value)
+	}
+}
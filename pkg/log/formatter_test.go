@@ -0,0 +1,40 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	got := render("%L %M", ts, _infoLevel, []D{KV(_log, "hello")})
+	if want := "INFO hello"; got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderFieldsBareMessageAndKV(t *testing.T) {
+	ts := time.Now()
+	got := render("%M", ts, _infoLevel, []D{KV(_log, "msg"), KV("user", "alice")})
+	if want := "msg user=alice"; got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderUnknownVerbPassesThrough(t *testing.T) {
+	got := render("%Z", time.Now(), _infoLevel, nil)
+	if want := "%Z"; got != want {
+		t.Errorf("render = %q, want %q", got, want)
+	}
+}
+
+func TestRenderDispatchesStructuredFormats(t *testing.T) {
+	ts := time.Now()
+	for _, format := range []string{"json", "logfmt"} {
+		got := render(format, ts, _infoLevel, []D{KV("k", "v")})
+		if !strings.Contains(got, "k") || !strings.Contains(got, "v") {
+			t.Errorf("render(%q) = %q, missing field", format, got)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bilibili/Kratos/pkg/log/core"
+)
+
+// _log is the field key used by the printf-style helpers (Info, Warn,
+// Error, ...) to carry the formatted message.
+const _log = "log"
+
+// Record is a single structured log entry passed to a Handler. See
+// core.Record for the canonical definition.
+type Record = core.Record
+
+// emit is the package-level API's adapter onto the Handler interface: it
+// checks Enabled before paying for Record construction and dispatch.
+func emit(ctx context.Context, level Level, fields ...D) {
+	if !h.Enabled(ctx, level) {
+		return
+	}
+	if err := h.Handle(ctx, Record{Level: level, Fields: fields}); err != nil {
+		fmt.Fprintf(os.Stderr, "log: handle: %v\n", err)
+	}
+}
+
+// Handler is the sink-facing logging interface, modeled on log/slog's
+// Handler contract so third-party sinks (Kafka, Loki, syslog, OTLP, ...)
+// can be plugged in without forking this package. See core.Handler for the
+// canonical definition; it lives there so pkg/log/filter can depend on it
+// without importing pkg/log back.
+type Handler = core.Handler
+
+// HandlerFactory builds a Handler from its configuration. Factories are
+// registered by name via RegisterHandler and looked up by Init from
+// Config.Handlers.
+type HandlerFactory func(conf HandlerConfig) (Handler, error)
+
+// HandlerConfig configures a single named handler instance. Raw carries
+// handler-specific settings (e.g. Dir/RotateSize for "file", Endpoint for
+// "otlp") and is interpreted by that handler's factory.
+type HandlerConfig struct {
+	Name string
+	Raw  interface{}
+}
+
+var _factories = make(map[string]HandlerFactory)
+
+// RegisterHandler registers a HandlerFactory under name so it can be
+// referenced from Config.Handlers. Calling RegisterHandler with a name
+// that's already registered overwrites the previous factory; this is most
+// often used by init() in a handler's own file, e.g. stdout.go calls
+// RegisterHandler("stdout", ...).
+func RegisterHandler(name string, factory HandlerFactory) {
+	_factories[name] = factory
+}
+
+// newHandler looks up conf.Name in the registry and builds a Handler from
+// it.
+func newHandler(conf HandlerConfig) (Handler, error) {
+	factory, ok := _factories[conf.Name]
+	if !ok {
+		return nil, fmt.Errorf("log: no handler registered for %q", conf.Name)
+	}
+	return factory(conf)
+}
+
+// base implements the attrs/group bookkeeping shared by the line-oriented
+// handlers (stdout, file). Embedding it provides the withAttrs/withGroup/
+// fields helpers; the embedding type still implements every Handler method
+// itself, with WithAttrs/WithGroup delegating to withAttrs/withGroup.
+type base struct {
+	attrs  []D
+	groups []string
+}
+
+func (b base) withAttrs(attrs []D) base {
+	next := make([]D, 0, len(b.attrs)+len(attrs))
+	next = append(next, b.attrs...)
+	next = append(next, attrs...)
+	b.attrs = next
+	return b
+}
+
+func (b base) withGroup(name string) base {
+	next := make([]string, 0, len(b.groups)+1)
+	next = append(next, b.groups...)
+	next = append(next, name)
+	b.groups = next
+	return b
+}
+
+// fields merges the handler's persistent attrs with rec, nesting all of it
+// under any active WithGroup names (innermost group first).
+func (b base) fields(rec []D) []D {
+	all := make([]D, 0, len(b.attrs)+len(rec))
+	all = append(all, b.attrs...)
+	all = append(all, rec...)
+	return nestFields(b.groups, all)
+}
+
+// nestFields wraps fields under groups, innermost group first, so e.g.
+// groups=["a","b"], fields=[{"k","v"}] becomes a single field
+// {"a": {"b": {"k": "v"}}}. Both (base).fields and (*Logger).emit use this
+// to give handlers and Loggers the same WithGroup representation.
+func nestFields(groups []string, fields []D) []D {
+	for i := len(groups) - 1; i >= 0; i-- {
+		nested := make(map[string]interface{}, len(fields))
+		for _, d := range fields {
+			nested[d.Key] = d.Value
+		}
+		fields = []D{KV(groups[i], nested)}
+	}
+	return fields
+}
+
+// multiHandler fans a Record out to every child handler, continuing past
+// individual failures so one broken sink can't silence the rest.
+type multiHandler []Handler
+
+func newHandlers(hs ...Handler) Handler {
+	if len(hs) == 1 {
+		return hs[0]
+	}
+	return multiHandler(hs)
+}
+
+func (hs multiHandler) Enabled(ctx context.Context, level Level) bool {
+	for _, h := range hs {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (hs multiHandler) Handle(ctx context.Context, r Record) (err error) {
+	for _, h := range hs {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if e := h.Handle(ctx, r); e != nil {
+			err = e
+		}
+	}
+	return
+}
+
+func (hs multiHandler) WithAttrs(attrs []D) Handler {
+	next := make(multiHandler, len(hs))
+	for i, h := range hs {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return next
+}
+
+func (hs multiHandler) WithGroup(name string) Handler {
+	next := make(multiHandler, len(hs))
+	for i, h := range hs {
+		next[i] = h.WithGroup(name)
+	}
+	return next
+}
+
+func (hs multiHandler) SetFormat(format string) {
+	for _, h := range hs {
+		h.SetFormat(format)
+	}
+}
+
+func (hs multiHandler) Close() (err error) {
+	for _, h := range hs {
+		if e := h.Close(); e != nil {
+			err = e
+		}
+	}
+	return
+}
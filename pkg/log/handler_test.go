@@ -0,0 +1,75 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type stubHandler struct {
+	base
+	enabled bool
+	handled int
+	failErr error
+}
+
+func (h *stubHandler) Enabled(context.Context, Level) bool { return h.enabled }
+func (h *stubHandler) Handle(context.Context, Record) error {
+	h.handled++
+	return h.failErr
+}
+func (h *stubHandler) WithAttrs(attrs []D) Handler {
+	next := *h
+	next.base = h.base.withAttrs(attrs)
+	return &next
+}
+func (h *stubHandler) WithGroup(name string) Handler {
+	next := *h
+	next.base = h.base.withGroup(name)
+	return &next
+}
+func (h *stubHandler) SetFormat(string) {}
+func (h *stubHandler) Close() error     { return h.failErr }
+
+func TestMultiHandlerEnabledIfAnyChildIs(t *testing.T) {
+	hs := newHandlers(&stubHandler{enabled: false}, &stubHandler{enabled: true})
+	if !hs.Enabled(context.Background(), _infoLevel) {
+		t.Fatal("Enabled = false, want true (one child enabled)")
+	}
+}
+
+func TestMultiHandlerHandleContinuesPastFailure(t *testing.T) {
+	failing := &stubHandler{enabled: true, failErr: errors.New("boom")}
+	ok := &stubHandler{enabled: true}
+	hs := newHandlers(failing, ok)
+
+	err := hs.Handle(context.Background(), Record{Level: _infoLevel})
+	if err == nil {
+		t.Fatal("Handle err = nil, want the failing child's error")
+	}
+	if failing.handled != 1 || ok.handled != 1 {
+		t.Fatalf("handled = (%d, %d), want (1, 1): one failure shouldn't stop the rest", failing.handled, ok.handled)
+	}
+}
+
+func TestMultiHandlerSkipsDisabledChildren(t *testing.T) {
+	disabled := &stubHandler{enabled: false}
+	enabled := &stubHandler{enabled: true}
+	hs := newHandlers(disabled, enabled)
+
+	hs.Handle(context.Background(), Record{Level: _infoLevel})
+
+	if disabled.handled != 0 {
+		t.Errorf("disabled.handled = %d, want 0", disabled.handled)
+	}
+	if enabled.handled != 1 {
+		t.Errorf("enabled.handled = %d, want 1", enabled.handled)
+	}
+}
+
+func TestNewHandlersSingleIsUnwrapped(t *testing.T) {
+	single := &stubHandler{enabled: true}
+	if got := newHandlers(single); got != Handler(single) {
+		t.Errorf("newHandlers(single) = %v, want the handler itself unwrapped", got)
+	}
+}
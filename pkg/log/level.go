@@ -0,0 +1,13 @@
+package log
+
+import "github.com/bilibili/Kratos/pkg/log/core"
+
+// Level identifies the severity of a log Record, ordered from least to most
+// severe. See core.Level for the canonical definition.
+type Level = core.Level
+
+const (
+	_infoLevel  = core.LevelInfo
+	_warnLevel  = core.LevelWarn
+	_errorLevel = core.LevelError
+)
@@ -7,9 +7,12 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/bilibili/Kratos/pkg/conf/env"
+	"github.com/bilibili/Kratos/pkg/log/core"
+	"github.com/bilibili/Kratos/pkg/log/filter"
 )
 
 // Config log config.
@@ -28,6 +31,15 @@ type Config struct {
 	MaxLogFile int
 	// RotateSize
 	RotateSize int64
+	// AsyncBufferEntries sizes the file handler's ring buffer; see
+	// FileConfig.AsyncBufferEntries.
+	AsyncBufferEntries int
+	// AsyncFlushInterval bounds how long a buffered entry can sit before
+	// being written; see FileConfig.AsyncFlushInterval.
+	AsyncFlushInterval time.Duration
+	// AsyncDropPolicy says what the file handler does when its ring
+	// buffer is full; see FileConfig.AsyncDropPolicy.
+	AsyncDropPolicy AsyncDropPolicy
 
 	// V Enable V-leveled logging at the specified level.
 	V int32
@@ -41,7 +53,19 @@ type Config struct {
 	// sets the V level to 2 in all Go files whose names begin "dao".
 	Module map[string]int32
 	// Filter tell log handler which field are sensitive message, use * instead.
+	//
+	// Deprecated: use Filters, which supports level/value/func matching and
+	// lets handlers stack multiple filters instead of one flat key list.
 	Filter []string
+	// Filters builds the redaction/sampling pipeline applied to every
+	// handler; see pkg/log/filter.
+	Filters []filter.FilterOption
+
+	// Handlers composes the active handler chain by name, e.g.
+	//   Handlers: []HandlerConfig{{Name: "stdout"}, {Name: "file", Raw: FileConfig{Dir: "/data/log"}}}
+	// Each name must have been registered via RegisterHandler. When empty,
+	// Init falls back to the legacy Stdout/Dir fields above.
+	Handlers []HandlerConfig
 }
 
 var (
@@ -104,92 +128,80 @@ func Init(conf *Config) {
 		conf.Host = host
 	}
 	var hs []Handler
-	// when env is dev
-	if isNil || conf.Stdout {
+	if len(conf.Handlers) > 0 {
+		for _, hc := range conf.Handlers {
+			hd, err := newHandler(hc)
+			if err != nil {
+				Error("log: %v", err)
+				continue
+			}
+			hs = append(hs, hd)
+		}
+	} else {
+		// legacy config: compose the hard-wired stdout/file combination.
+		if isNil || conf.Stdout {
+			hs = append(hs, NewStdout())
+		}
+		if conf.Dir != "" {
+			hs = append(hs, NewFile(FileConfig{
+				Dir:                conf.Dir,
+				BufferSize:         conf.FileBufferSize,
+				RotateSize:         conf.RotateSize,
+				MaxLogFile:         conf.MaxLogFile,
+				AsyncBufferEntries: conf.AsyncBufferEntries,
+				AsyncFlushInterval: conf.AsyncFlushInterval,
+				AsyncDropPolicy:    conf.AsyncDropPolicy,
+			}))
+		}
+	}
+	if len(hs) == 0 {
 		hs = append(hs, NewStdout())
 	}
-	if conf.Dir != "" {
-		hs = append(hs, NewFile(conf.Dir, conf.FileBufferSize, conf.RotateSize, conf.MaxLogFile))
+	opts := conf.Filters
+	if len(conf.Filter) > 0 {
+		opts = append(opts, filter.FilterKey(conf.Filter...))
+	}
+	if len(opts) > 0 {
+		for i, hd := range hs {
+			hs[i] = filter.NewFilter(hd, opts...)
+		}
+	}
+	h = newHandlers(hs...)
+	if vConfigChanged(c, conf) {
+		atomic.AddInt64(&_vmoduleGen, 1)
 	}
-	h = newHandlers(conf.Filter, hs...)
 	c = conf
 }
 
-// D represents a map of entry level data used for structured logging.
-// type D map[string]interface{}
-type D struct {
-	Key   string
-	Value interface{}
-}
-
-// AddTo exports a field through the ObjectEncoder interface. It's primarily
-// useful to library authors, and shouldn't be necessary in most applications.
-func (d D) AddTo(enc core.ObjectEncoder) {
-	var err error
-	switch val := d.Value.(type) {
-	case bool:
-		enc.AddBool(d.Key, val)
-	case complex128:
-		enc.AddComplex128(d.Key, val)
-	case complex64:
-		enc.AddComplex64(d.Key, val)
-	case float64:
-		enc.AddFloat64(d.Key, val)
-	case float32:
-		enc.AddFloat32(d.Key, val)
-	case int:
-		enc.AddInt(d.Key, val)
-	case int64:
-		enc.AddInt64(d.Key, val)
-	case int32:
-		enc.AddInt32(d.Key, val)
-	case int16:
-		enc.AddInt16(d.Key, val)
-	case int8:
-		enc.AddInt8(d.Key, val)
-	case string:
-		enc.AddString(d.Key, val)
-	case uint:
-		enc.AddUint(d.Key, val)
-	case uint64:
-		enc.AddUint64(d.Key, val)
-	case uint32:
-		enc.AddUint32(d.Key, val)
-	case uint16:
-		enc.AddUint16(d.Key, val)
-	case uint8:
-		enc.AddUint8(d.Key, val)
-	case []byte:
-		enc.AddByteString(d.Key, val)
-	case uintptr:
-		enc.AddUintptr(d.Key, val)
-	case time.Time:
-		enc.AddTime(d.Key, val)
-	case xtime.Time:
-		enc.AddTime(d.Key, val.Time())
-	case time.Duration:
-		enc.AddDuration(d.Key, val)
-	case xtime.Duration:
-		enc.AddDuration(d.Key, time.Duration(val))
-	case error:
-		enc.AddString(d.Key, val.Error())
-	case fmt.Stringer:
-		enc.AddString(d.Key, val.String())
-	default:
-		err = enc.AddReflected(d.Key, val)
+// vConfigChanged reports whether next's V/Module differ from old's,
+// meaning every cached vDepth decision (keyed by call site) is stale and
+// must be re-evaluated against the new config.
+func vConfigChanged(old, next *Config) bool {
+	if old == nil {
+		return true
 	}
-
-	if err != nil {
-		enc.AddString(fmt.Sprintf("%sError", d.Key), err.Error())
+	if old.V != next.V {
+		return true
 	}
+	if len(old.Module) != len(next.Module) {
+		return true
+	}
+	for k, v := range next.Module {
+		if ov, ok := old.Module[k]; !ok || ov != v {
+			return true
+		}
+	}
+	return false
 }
 
+// D represents a single entry of structured logging data. See core.D for
+// the canonical definition; it lives there so pkg/log/filter can depend on
+// it without importing pkg/log back.
+type D = core.D
+
 // KV return a log kv for logging field.
 func KV(key string, value interface{}) D {
-	return D{
-		Key:   key,
-		Value: value,
-	}
+	return core.KV(key, value)
 }
 
 type logFilter []string
@@ -209,50 +221,60 @@ func (f *logFilter) Set(value string) error {
 
 // Info logs a message at the info log level.
 func Info(format string, args ...interface{}) {
-	h.Log(context.Background(), _infoLevel, KV(_log, fmt.Sprintf(format, args...)))
+	emit(context.Background(), _infoLevel, KV(_log, fmt.Sprintf(format, args...)))
 }
 
 // Warn logs a message at the warning log level.
 func Warn(format string, args ...interface{}) {
-	h.Log(context.Background(), _warnLevel, KV(_log, fmt.Sprintf(format, args...)))
+	emit(context.Background(), _warnLevel, KV(_log, fmt.Sprintf(format, args...)))
 }
 
 // Error logs a message at the error log level.
 func Error(format string, args ...interface{}) {
-	h.Log(context.Background(), _errorLevel, KV(_log, fmt.Sprintf(format, args...)))
+	emit(context.Background(), _errorLevel, KV(_log, fmt.Sprintf(format, args...)))
 }
 
-// Infoc logs a message at the info log level.
+// Infoc logs a message at the info log level, including any fields
+// attached to ctx via NewContext.
 func Infoc(ctx context.Context, format string, args ...interface{}) {
-	h.Log(ctx, _infoLevel, KV(_log, fmt.Sprintf(format, args...)))
+	FromContext(ctx).emit(ctx, _infoLevel, KV(_log, fmt.Sprintf(format, args...)))
 }
 
-// Errorc logs a message at the error log level.
+// Errorc logs a message at the error log level, including any fields
+// attached to ctx via NewContext.
 func Errorc(ctx context.Context, format string, args ...interface{}) {
-	h.Log(ctx, _errorLevel, KV(_log, fmt.Sprintf(format, args...)))
+	FromContext(ctx).emit(ctx, _errorLevel, KV(_log, fmt.Sprintf(format, args...)))
 }
 
-// Warnc logs a message at the warning log level.
+// Warnc logs a message at the warning log level, including any fields
+// attached to ctx via NewContext.
 func Warnc(ctx context.Context, format string, args ...interface{}) {
-	h.Log(ctx, _warnLevel, KV(_log, fmt.Sprintf(format, args...)))
+	FromContext(ctx).emit(ctx, _warnLevel, KV(_log, fmt.Sprintf(format, args...)))
 }
 
-// Infov logs a message at the info log level.
+// Infov logs a message at the info log level, including any fields
+// attached to ctx via NewContext.
 func Infov(ctx context.Context, args ...D) {
-	h.Log(ctx, _infoLevel, args...)
+	FromContext(ctx).emit(ctx, _infoLevel, args...)
 }
 
-// Warnv logs a message at the warning log level.
+// Warnv logs a message at the warning log level, including any fields
+// attached to ctx via NewContext.
 func Warnv(ctx context.Context, args ...D) {
-	h.Log(ctx, _warnLevel, args...)
+	FromContext(ctx).emit(ctx, _warnLevel, args...)
 }
 
-// Errorv logs a message at the error log level.
+// Errorv logs a message at the error log level, including any fields
+// attached to ctx via NewContext.
 func Errorv(ctx context.Context, args ...D) {
-	h.Log(ctx, _errorLevel, args...)
+	FromContext(ctx).emit(ctx, _errorLevel, args...)
 }
 
-// SetFormat only effective on stdout and file handler
+// SetFormat only effective on stdout and file handler. format is either a
+// %-template or the name of a structured encoder:
+//   "json"   one JSON object per record, RFC3339Nano timestamps
+//   "logfmt" one "key=value ..." line per record, values quoted as needed
+// %-template reference:
 // %T time format at "15:04:05.999" on stdout handler, "15:04:05 MST" on file handler
 // %t time format at "15:04:05" on stdout handler, "15:04" on file on file handler
 // %D data format at "2006/01/02"
@@ -270,19 +292,25 @@ func SetFormat(format string) {
 	h.SetFormat(format)
 }
 
-// Infow logs a message with some additional context. The variadic key-value pairs are treated as they are in With.
+// Infow logs a message with some additional context, including any fields
+// attached to ctx via NewContext. args are alternating key/value pairs
+// whose keys must be strings.
 func Infow(ctx context.Context, args ...interface{}) {
-	h.Log(ctx, _infoLevel, logw(args)...)
+	FromContext(ctx).emit(ctx, _infoLevel, logw(args)...)
 }
 
-// Warnw logs a message with some additional context. The variadic key-value pairs are treated as they are in With.
+// Warnw logs a message with some additional context, including any fields
+// attached to ctx via NewContext. args are alternating key/value pairs
+// whose keys must be strings.
 func Warnw(ctx context.Context, args ...interface{}) {
-	h.Log(ctx, _warnLevel, logw(args)...)
+	FromContext(ctx).emit(ctx, _warnLevel, logw(args)...)
 }
 
-// Errorw logs a message with some additional context. The variadic key-value pairs are treated as they are in With.
+// Errorw logs a message with some additional context, including any fields
+// attached to ctx via NewContext. args are alternating key/value pairs
+// whose keys must be strings.
 func Errorw(ctx context.Context, args ...interface{}) {
-	h.Log(ctx, _errorLevel, logw(args)...)
+	FromContext(ctx).emit(ctx, _errorLevel, logw(args)...)
 }
 
 func logw(args []interface{}) []D {
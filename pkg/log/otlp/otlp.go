@@ -0,0 +1,229 @@
+// Package otlp is a pkg/log Handler that batches records and exports them
+// to an OTLP/gRPC log collector.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+
+	"github.com/bilibili/Kratos/pkg/log"
+)
+
+func init() {
+	log.RegisterHandler("otlp", func(conf log.HandlerConfig) (log.Handler, error) {
+		oc, ok := conf.Raw.(Config)
+		if !ok {
+			return nil, fmt.Errorf("log: otlp handler requires a Config, got %T", conf.Raw)
+		}
+		return New(oc)
+	})
+}
+
+// Config configures the OTLP log sink.
+type Config struct {
+	// Endpoint is the OTLP/gRPC collector address, e.g. "otel-collector:4317".
+	Endpoint string
+	// Insecure disables TLS on the gRPC connection.
+	Insecure bool
+	// BatchSize caps how many records accumulate before an Export call;
+	// defaults to 512.
+	BatchSize int
+	// FlushInterval bounds how long a record can wait in the batch before
+	// being sent regardless of BatchSize; defaults to 5s.
+	FlushInterval time.Duration
+	// DialTimeout bounds the initial connection attempt; defaults to 5s.
+	DialTimeout time.Duration
+}
+
+const (
+	_defaultBatchSize     = 512
+	_defaultFlushInterval = 5 * time.Second
+	_defaultDialTimeout   = 5 * time.Second
+	_exportTimeout        = 10 * time.Second
+)
+
+// state is the gRPC connection and pending batch shared by every view of a
+// handler produced via WithAttrs/WithGroup; it's referenced through a
+// pointer so copying a handler (as WithAttrs/WithGroup do) never copies the
+// embedded sync.Mutex.
+type state struct {
+	conn   *grpc.ClientConn
+	client collogspb.LogsServiceClient
+
+	mu      sync.Mutex
+	pending []*logspb.LogRecord
+
+	batchSize  int
+	flushEvery time.Duration
+
+	wake    chan struct{}
+	done    chan struct{}
+	drained chan struct{}
+}
+
+// handler is a log.Handler that exports records to an OTLP/gRPC collector.
+type handler struct {
+	state *state
+
+	attrs  []log.D
+	groups []string
+}
+
+// New dials conf.Endpoint and returns a Handler that streams records to it.
+// The connection and background export loop are shared by every view
+// returned from WithAttrs/WithGroup.
+func New(conf Config) (log.Handler, error) {
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = _defaultBatchSize
+	}
+	if conf.FlushInterval <= 0 {
+		conf.FlushInterval = _defaultFlushInterval
+	}
+	if conf.DialTimeout <= 0 {
+		conf.DialTimeout = _defaultDialTimeout
+	}
+	dialCtx, cancel := context.WithTimeout(context.Background(), conf.DialTimeout)
+	defer cancel()
+	// WithBlock makes DialContext wait for the connection, so DialTimeout
+	// bounds it.
+	dialOpts := []grpc.DialOption{grpc.WithBlock()}
+	if conf.Insecure {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(nil)))
+	}
+	conn, err := grpc.DialContext(dialCtx, conf.Endpoint, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("log: otlp: dial %s: %w", conf.Endpoint, err)
+	}
+	s := &state{
+		conn:       conn,
+		client:     collogspb.NewLogsServiceClient(conn),
+		batchSize:  conf.BatchSize,
+		flushEvery: conf.FlushInterval,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		drained:    make(chan struct{}),
+	}
+	go s.loop()
+	return &handler{state: s}, nil
+}
+
+func (h *handler) Enabled(context.Context, log.Level) bool { return true }
+
+func (h *handler) Handle(ctx context.Context, r log.Record) error {
+	h.state.push(toLogRecord(ctx, r.Level, h.fields(r.Fields)))
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []log.D) log.Handler {
+	next := *h
+	next.attrs = append(append([]log.D(nil), h.attrs...), attrs...)
+	return &next
+}
+
+func (h *handler) WithGroup(name string) log.Handler {
+	next := *h
+	next.groups = append(append([]string(nil), h.groups...), name)
+	return &next
+}
+
+// fields merges the handler's persistent attrs with rec and prefixes every
+// key with the dotted group path. Unlike stdout/file/the JSON encoder,
+// OTLP attributes are a flat list rather than nested objects, so WithGroup
+// is represented as a "group.key" name instead of nesting.
+func (h *handler) fields(rec []log.D) []log.D {
+	all := make([]log.D, 0, len(h.attrs)+len(rec))
+	all = append(all, h.attrs...)
+	all = append(all, rec...)
+	if len(h.groups) == 0 {
+		return all
+	}
+	prefix := strings.Join(h.groups, ".") + "."
+	out := make([]log.D, len(all))
+	for i, d := range all {
+		out[i] = log.KV(prefix+d.Key, d.Value)
+	}
+	return out
+}
+
+// SetFormat is a no-op: OTLP records are always structured protobuf, so
+// there is no line template to select.
+func (h *handler) SetFormat(string) {}
+
+func (h *handler) Close() error {
+	return h.state.close()
+}
+
+func (s *state) push(r *logspb.LogRecord) {
+	s.mu.Lock()
+	s.pending = append(s.pending, r)
+	full := len(s.pending) >= s.batchSize
+	s.mu.Unlock()
+	if full {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// loop drains the pending batch on whichever comes first: BatchSize worth
+// of records, the flush ticker, or Close.
+func (s *state) loop() {
+	defer close(s.drained)
+	t := time.NewTicker(s.flushEvery)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.wake:
+			s.flush()
+		case <-t.C:
+			s.flush()
+		case <-s.done:
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *state) flush() {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	req := &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			ScopeLogs: []*logspb.ScopeLogs{{
+				LogRecords: batch,
+			}},
+		}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), _exportTimeout)
+	defer cancel()
+	if _, err := s.client.Export(ctx, req); err != nil {
+		fmt.Fprintf(os.Stderr, "log: otlp: export: %v\n", err)
+	}
+}
+
+func (s *state) close() error {
+	close(s.done)
+	<-s.drained
+	return s.conn.Close()
+}
@@ -0,0 +1,94 @@
+package otlp
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeClient is a collogspb.LogsServiceClient stub: embedding the nil
+// interface satisfies every method, and Export records whatever batches it
+// was handed instead of making a network call.
+type fakeClient struct {
+	collogspb.LogsServiceClient
+
+	mu      sync.Mutex
+	batches [][]*logspb.LogRecord
+}
+
+func (f *fakeClient) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest, _ ...grpc.CallOption) (*collogspb.ExportLogsServiceResponse, error) {
+	var batch []*logspb.LogRecord
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			batch = append(batch, sl.LogRecords...)
+		}
+	}
+	f.mu.Lock()
+	f.batches = append(f.batches, batch)
+	f.mu.Unlock()
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (f *fakeClient) exported() []*logspb.LogRecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var all []*logspb.LogRecord
+	for _, b := range f.batches {
+		all = append(all, b...)
+	}
+	return all
+}
+
+func newTestState(client *fakeClient, batchSize int) *state {
+	return &state{
+		client:     client,
+		batchSize:  batchSize,
+		flushEvery: time.Hour, // never fires on its own; tests drive flushes explicitly
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		drained:    make(chan struct{}),
+	}
+}
+
+func TestStatePushFlushesAtBatchSize(t *testing.T) {
+	client := &fakeClient{}
+	s := newTestState(client, 2)
+	go s.loop()
+	defer func() {
+		close(s.done)
+		<-s.drained
+	}()
+
+	s.push(&logspb.LogRecord{SeverityText: "a"})
+	s.push(&logspb.LogRecord{SeverityText: "b"})
+
+	deadline := time.After(time.Second)
+	for len(client.exported()) < 2 {
+		select {
+		case <-deadline:
+			t.Fatalf("got %d exported records, want 2", len(client.exported()))
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestStateCloseFlushesPendingBelowBatchSize(t *testing.T) {
+	client := &fakeClient{}
+	s := newTestState(client, 10)
+	go s.loop()
+
+	s.push(&logspb.LogRecord{SeverityText: "only"})
+
+	close(s.done)
+	<-s.drained
+
+	got := client.exported()
+	if len(got) != 1 || got[0].SeverityText != "only" {
+		t.Fatalf("exported = %+v, want one record with SeverityText \"only\"", got)
+	}
+}
@@ -0,0 +1,92 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+
+	"github.com/bilibili/Kratos/pkg/log"
+	"github.com/bilibili/Kratos/pkg/log/core"
+	"github.com/bilibili/Kratos/pkg/net/trace"
+)
+
+// toLogRecord converts a pkg/log Record into its OTLP wire form, pulling
+// the trace/span IDs out of ctx if one is attached (see pkg/net/trace).
+func toLogRecord(ctx context.Context, level log.Level, fields []log.D) *logspb.LogRecord {
+	now := uint64(time.Now().UnixNano())
+	r := &logspb.LogRecord{
+		TimeUnixNano:         now,
+		ObservedTimeUnixNano: now,
+		SeverityNumber:       severityNumber(level),
+		SeverityText:         level.String(),
+	}
+	if t, ok := trace.FromContext(ctx); ok {
+		r.TraceId = []byte(t.TraceID())
+		r.SpanId = []byte(t.SpanID())
+	}
+	for _, d := range fields {
+		// the printf-style helpers (Info, Warn, ...) carry their message
+		// under this key; everything else is an attribute.
+		if d.Key == "log" {
+			r.Body = toAnyValue(d.Value)
+			continue
+		}
+		r.Attributes = append(r.Attributes, toKeyValue(d.Key, d.Value))
+	}
+	return r
+}
+
+func severityNumber(level log.Level) logspb.SeverityNumber {
+	switch level {
+	case core.LevelInfo:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO
+	case core.LevelWarn:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case core.LevelError:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED
+	}
+}
+
+func toKeyValue(k string, v interface{}) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: k, Value: toAnyValue(v)}
+}
+
+// toAnyValue maps a field's Go value onto the OTLP AnyValue oneof. Types
+// with no direct counterpart (durations, timestamps, Stringers, ...) are
+// rendered as strings; anything else, including the nested maps WithGroup
+// produces, is rendered with fmt's %v.
+func toAnyValue(v interface{}) *commonpb.AnyValue {
+	switch x := v.(type) {
+	case nil:
+		return &commonpb.AnyValue{}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: x}}
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: x}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(x)}}
+	case int32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(x)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: x}}
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(x)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: x}}
+	case time.Time:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: x.Format(time.RFC3339Nano)}}
+	case time.Duration:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: x.String()}}
+	case fmt.Stringer:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: x.String()}}
+	case error:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: x.Error()}}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprintf("%v", x)}}
+	}
+}
@@ -0,0 +1,90 @@
+package log
+
+import "sync/atomic"
+
+// ringBuffer is a bounded, lock-free multi-producer/multi-consumer queue
+// of fileRecord, based on Dmitry Vyukov's MPMC bounded queue design: each
+// slot carries its own sequence number so producers and consumers claim
+// slots with a single CAS instead of a shared lock.
+type ringBuffer struct {
+	buf  []ringCell
+	mask uint64
+
+	enqueuePos uint64
+	dequeuePos uint64
+}
+
+type ringCell struct {
+	seq uint64
+	val fileRecord
+}
+
+// newRingBuffer returns a ringBuffer with room for at least capacity
+// entries, rounded up to the next power of two.
+func newRingBuffer(capacity int) *ringBuffer {
+	n := 1
+	for n < capacity {
+		n <<= 1
+	}
+	buf := make([]ringCell, n)
+	for i := range buf {
+		buf[i].seq = uint64(i)
+	}
+	return &ringBuffer{buf: buf, mask: uint64(n - 1)}
+}
+
+// push claims a slot and stores v, returning false if the buffer is full.
+func (q *ringBuffer) push(v fileRecord) bool {
+	pos := atomic.LoadUint64(&q.enqueuePos)
+	for {
+		cell := &q.buf[pos&q.mask]
+		seq := atomic.LoadUint64(&cell.seq)
+		diff := int64(seq) - int64(pos)
+		switch {
+		case diff == 0:
+			if atomic.CompareAndSwapUint64(&q.enqueuePos, pos, pos+1) {
+				cell.val = v
+				atomic.StoreUint64(&cell.seq, pos+1)
+				return true
+			}
+		case diff < 0:
+			return false // full
+		default:
+			pos = atomic.LoadUint64(&q.enqueuePos)
+		}
+	}
+}
+
+// pop claims the oldest queued entry, returning ok=false if the buffer is
+// empty.
+func (q *ringBuffer) pop() (v fileRecord, ok bool) {
+	pos := atomic.LoadUint64(&q.dequeuePos)
+	for {
+		cell := &q.buf[pos&q.mask]
+		seq := atomic.LoadUint64(&cell.seq)
+		diff := int64(seq) - int64(pos+1)
+		switch {
+		case diff == 0:
+			if atomic.CompareAndSwapUint64(&q.dequeuePos, pos, pos+1) {
+				v = cell.val
+				atomic.StoreUint64(&cell.seq, pos+q.mask+1)
+				return v, true
+			}
+		case diff < 0:
+			return fileRecord{}, false // empty
+		default:
+			pos = atomic.LoadUint64(&q.dequeuePos)
+		}
+	}
+}
+
+// len reports an approximate number of entries currently queued; it's a
+// racy snapshot useful only for Stats(), not for correctness.
+func (q *ringBuffer) len() int64 {
+	enq := atomic.LoadUint64(&q.enqueuePos)
+	deq := atomic.LoadUint64(&q.dequeuePos)
+	if enq < deq {
+		return 0
+	}
+	return int64(enq - deq)
+}
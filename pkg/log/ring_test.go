@@ -0,0 +1,41 @@
+package log
+
+import "testing"
+
+func TestRingBufferPushPop(t *testing.T) {
+	r := newRingBuffer(2) // rounds up to 2
+	if !r.push(fileRecord{level: _infoLevel}) {
+		t.Fatal("push 1 failed")
+	}
+	if !r.push(fileRecord{level: _warnLevel}) {
+		t.Fatal("push 2 failed")
+	}
+	if r.push(fileRecord{level: _errorLevel}) {
+		t.Fatal("push into a full buffer should fail")
+	}
+
+	v, ok := r.pop()
+	if !ok || v.level != _infoLevel {
+		t.Fatalf("pop 1 = (%v, %v), want (_infoLevel, true)", v.level, ok)
+	}
+	v, ok = r.pop()
+	if !ok || v.level != _warnLevel {
+		t.Fatalf("pop 2 = (%v, %v), want (_warnLevel, true)", v.level, ok)
+	}
+	if _, ok = r.pop(); ok {
+		t.Fatal("pop from an empty buffer should fail")
+	}
+}
+
+func TestRingBufferLen(t *testing.T) {
+	r := newRingBuffer(4)
+	r.push(fileRecord{})
+	r.push(fileRecord{})
+	if n := r.len(); n != 2 {
+		t.Errorf("len = %d, want 2", n)
+	}
+	r.pop()
+	if n := r.len(); n != 1 {
+		t.Errorf("len = %d, want 1", n)
+	}
+}
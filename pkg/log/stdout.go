@@ -0,0 +1,57 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+func init() {
+	RegisterHandler("stdout", func(HandlerConfig) (Handler, error) {
+		return NewStdout(), nil
+	})
+}
+
+// stdout writes records to os.Stdout using the %T %L %M printf-style
+// template (see SetFormat).
+type stdout struct {
+	base
+	format string
+}
+
+// _defaultStdout is used by Close to restore a safe handler once the
+// configured one has been torn down.
+var _defaultStdout Handler = NewStdout()
+
+// NewStdout returns a Handler that writes to os.Stdout.
+func NewStdout() Handler {
+	return &stdout{format: "%T %L %M"}
+}
+
+func (s *stdout) Enabled(context.Context, Level) bool { return true }
+
+func (s *stdout) Handle(ctx context.Context, r Record) error {
+	_, err := fmt.Fprintln(os.Stdout, render(s.format, time.Now(), r.Level, s.fields(r.Fields)))
+	return err
+}
+
+func (s *stdout) WithAttrs(attrs []D) Handler {
+	next := *s
+	next.base = s.base.withAttrs(attrs)
+	return &next
+}
+
+func (s *stdout) WithGroup(name string) Handler {
+	next := *s
+	next.base = s.base.withGroup(name)
+	return &next
+}
+
+func (s *stdout) SetFormat(format string) {
+	if format != "" {
+		s.format = format
+	}
+}
+
+func (s *stdout) Close() error { return nil }
@@ -0,0 +1,172 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// verboseModule implements flag.Value for the -log.module flag (and
+// Config.Module), mapping glob patterns to V levels.
+//
+// A pattern matches either the file's basename minus its ".go" suffix (the
+// original behavior, e.g. "dao*=2") or, when the pattern contains a "/",
+// as many trailing segments of the file path reported by runtime.Caller
+// as the pattern has (e.g. "pkg/dao/*=2", "pkg/service/user.go=3").
+type verboseModule map[string]int32
+
+func (m verboseModule) String() string {
+	parts := make([]string, 0, len(m))
+	for k, v := range m {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, v))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses "pattern=level,pattern2=level2" and replaces the current
+// pattern set. Re-parsing invalidates the per-call-site V cache; V reads
+// its patterns from Config.Module, so new patterns apply once Init has
+// installed them there.
+func (m *verboseModule) Set(value string) error {
+	next := verboseModule{}
+	for _, kv := range strings.Split(value, ",") {
+		if kv == "" {
+			continue
+		}
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("log: invalid -log.module entry %q", kv)
+		}
+		level, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("log: invalid -log.module level in %q: %v", kv, err)
+		}
+		next[parts[0]] = int32(level)
+	}
+	*m = next
+	atomic.AddInt64(&_vmoduleGen, 1)
+	return nil
+}
+
+// level returns the highest V level configured for file (the full path as
+// given by runtime.Caller), or ok=false if no pattern matches.
+func (m verboseModule) level(file string) (level int32, ok bool) {
+	base := strings.TrimSuffix(filepath.Base(file), ".go")
+	fileSegments := strings.Split(filepath.ToSlash(file), "/")
+	for pattern, pl := range m {
+		var hit bool
+		if strings.Contains(pattern, "/") {
+			// runtime.Caller always reports an absolute (or module-qualified)
+			// path, so anchor the pattern to the file's trailing path
+			// segments rather than the whole string.
+			patternSegments := strings.Split(pattern, "/")
+			if len(patternSegments) <= len(fileSegments) {
+				suffix := strings.Join(fileSegments[len(fileSegments)-len(patternSegments):], "/")
+				hit, _ = filepath.Match(pattern, suffix)
+			}
+		} else {
+			hit, _ = filepath.Match(pattern, base)
+		}
+		if hit && (!ok || pl > level) {
+			level, ok = pl, true
+		}
+	}
+	return
+}
+
+// _vmoduleGen counts how many times -log.module/Config.Module has been
+// (re-)parsed; it's embedded in every V cache entry so a re-parse
+// invalidates stale decisions without having to walk the cache.
+var _vmoduleGen int64
+
+type vcacheEntry struct {
+	gen     int64
+	level   int32
+	matched bool
+}
+
+// _vcache memoizes the -log.module pattern match per call site, keyed by
+// the runtime.Caller program counter, so glob matching runs at most once
+// per source line.
+var _vcache sync.Map // map[uintptr]vcacheEntry
+
+// Verbose is returned by V/VDepth; its methods no-op when the V level
+// isn't enabled for the calling file, mirroring glog's Verbose API.
+type Verbose bool
+
+// Info logs args at the info level, using fmt.Sprint formatting, if v is
+// enabled.
+func (v Verbose) Info(args ...interface{}) {
+	if v {
+		Info("%s", fmt.Sprint(args...))
+	}
+}
+
+// Infof logs a formatted message at the info level if v is enabled.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v {
+		Info(format, args...)
+	}
+}
+
+// Infow logs a message with additional context at the info level if v is
+// enabled.
+func (v Verbose) Infow(ctx context.Context, args ...interface{}) {
+	if v {
+		Infow(ctx, args...)
+	}
+}
+
+// V reports whether verbose logging at level is enabled for the caller's
+// file, either via the global Config.V or a matching Config.Module/
+// -log.module pattern. The pattern match is cached per call site (keyed
+// by program counter) so repeated calls from the same log line only
+// evaluate glob patterns once.
+func V(level int32) Verbose {
+	return vDepth(1, level)
+}
+
+// VDepth is V for a call site one or more stack frames above the actual
+// caller, e.g. a library wrapper that wants V to reflect its own caller's
+// file rather than its own.
+func VDepth(depth int, level int32) Verbose {
+	return vDepth(depth+1, level)
+}
+
+func vDepth(depth int, level int32) Verbose {
+	pc, file, _, ok := runtime.Caller(depth + 1)
+	if !ok {
+		return Verbose(level <= confV())
+	}
+	gen := atomic.LoadInt64(&_vmoduleGen)
+	e, found := _vcache.Load(pc)
+	ce, _ := e.(vcacheEntry)
+	if !found || ce.gen != gen {
+		ml, matched := confModule().level(file)
+		ce = vcacheEntry{gen: gen, level: ml, matched: matched}
+		_vcache.Store(pc, ce)
+	}
+	if ce.matched {
+		return Verbose(level <= ce.level)
+	}
+	return Verbose(level <= confV())
+}
+
+func confV() int32 {
+	if c == nil {
+		return 0
+	}
+	return c.V
+}
+
+func confModule() verboseModule {
+	if c == nil {
+		return nil
+	}
+	return verboseModule(c.Module)
+}
@@ -0,0 +1,67 @@
+package log
+
+import "testing"
+
+func TestVerboseModuleLevel(t *testing.T) {
+	m := verboseModule{
+		"dao*":            2,
+		"pkg/service/*":   3,
+		"pkg/dao/user.go": 5,
+	}
+	cases := []struct {
+		file      string
+		wantLevel int32
+		wantOK    bool
+	}{
+		{"/src/app/dao_user.go", 2, true},
+		{"/src/app/pkg/service/user.go", 3, true},
+		{"pkg/dao/user.go", 5, true},
+		{"/src/app/unrelated.go", 0, false},
+	}
+	for _, c := range cases {
+		level, ok := m.level(c.file)
+		if ok != c.wantOK || level != c.wantLevel {
+			t.Errorf("level(%q) = (%d, %v), want (%d, %v)", c.file, level, ok, c.wantLevel, c.wantOK)
+		}
+	}
+}
+
+func TestVerboseModuleSetInvalidatesCache(t *testing.T) {
+	var m verboseModule
+	if err := m.Set("dao*=2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if level, ok := m.level("dao_test.go"); !ok || level != 2 {
+		t.Fatalf("level = (%d, %v), want (2, true)", level, ok)
+	}
+	gen := _vmoduleGen
+	if err := m.Set("dao*=4"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _vmoduleGen == gen {
+		t.Fatal("Set did not bump the V cache generation")
+	}
+}
+
+func TestInitBumpsVCacheOnConfigChange(t *testing.T) {
+	old := c
+	defer func() { c = old }()
+
+	Init(&Config{V: 0})
+	gen := _vmoduleGen
+	Init(&Config{V: 0})
+	if _vmoduleGen != gen {
+		t.Fatal("Init bumped the V cache generation despite an unchanged config")
+	}
+
+	Init(&Config{V: 5})
+	if _vmoduleGen == gen {
+		t.Fatal("Init did not bump the V cache generation after V changed")
+	}
+
+	gen = _vmoduleGen
+	Init(&Config{V: 5, Module: map[string]int32{"dao*": 2}})
+	if _vmoduleGen == gen {
+		t.Fatal("Init did not bump the V cache generation after Module changed")
+	}
+}